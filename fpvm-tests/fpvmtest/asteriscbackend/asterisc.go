@@ -0,0 +1,134 @@
+// Package asteriscbackend implements fpvmtest.VMBackend for the RISC-V
+// Asterisc VM. It is its own package (rather than living in fpvmtest
+// directly) so that consumers of the Cannon backend don't have to pull in
+// asterisc's fast package and its dependency tree.
+package asteriscbackend
+
+import (
+	"debug/elf"
+	"io"
+
+	"github.com/ethereum-optimism/asterisc/rvgo/fast"
+
+	"github.com/anton-rs/kona/fpvm-tests/fpvmtest"
+)
+
+// Backend drives a fault-proof program through the RISC-V Asterisc VM.
+type Backend struct{}
+
+var _ fpvmtest.VMBackend = Backend{}
+
+func (Backend) Name() string { return "asterisc" }
+
+func (Backend) LoadELF(programELF *elf.File, po fpvmtest.PreimageOracle, stdOut, stdErr io.Writer) (fpvmtest.VMState, fpvmtest.Symbols, error) {
+	vmState, err := fast.LoadELF(programELF)
+	if err != nil {
+		return nil, nil, err
+	}
+	symbols, err := fast.Symbols(programELF)
+	if err != nil {
+		return nil, nil, err
+	}
+	instState := fast.NewInstrumentedState(vmState, po, stdOut, stdErr)
+	return &state{vmState: vmState, instState: instState}, symbols, nil
+}
+
+type state struct {
+	vmState   *fast.VMState
+	instState *fast.InstrumentedState
+	callDepth uint64
+}
+
+var (
+	_ fpvmtest.VMState    = (*state)(nil)
+	_ fpvmtest.TraceHooks = (*state)(nil)
+)
+
+func (s *state) PC() uint64            { return s.vmState.PC }
+func (s *state) Register(i int) uint64 { return s.vmState.Registers[i] }
+func (s *state) Instr() uint32         { return s.vmState.Instr() }
+func (s *state) Exited() bool          { return s.vmState.Exited }
+func (s *state) ExitCode() uint8       { return s.vmState.ExitCode }
+func (s *state) CallDepth() uint64     { return s.callDepth }
+
+func (s *state) ReadMemoryRange(addr, size uint64) []byte {
+	r := s.vmState.Memory.ReadMemoryRange(addr, size)
+	dat, _ := io.ReadAll(r)
+	return dat
+}
+
+// Syscall reports the number in a7 (register 17) when the current
+// instruction is a RISC-V ECALL (opcode SYSTEM, imm 0; imm 1 is EBREAK).
+func (s *state) Syscall() (num uint64, ok bool) {
+	instr := uint64(s.Instr())
+	if instr&0x7f == 0x73 && instr>>20 == 0 {
+		return s.Register(17), true
+	}
+	return 0, false
+}
+
+// MemoryAccess reports the effective address and size of the load/store the
+// current RISC-V instruction performs, if any (opcode LOAD=0x03 or
+// STORE=0x23; funct3 selects byte/half/word/double width).
+func (s *state) MemoryAccess() (addr, size uint64, ok bool) {
+	instr := uint64(s.Instr())
+	opcode := instr & 0x7f
+	funct3 := (instr >> 12) & 0x7
+	rs1 := int((instr >> 15) & 0x1f)
+
+	switch funct3 {
+	case 0, 4:
+		size = 1
+	case 1, 5:
+		size = 2
+	case 2, 6:
+		size = 4
+	case 3:
+		size = 8
+	default:
+		return 0, 0, false
+	}
+
+	switch opcode {
+	case 0x03: // load: I-type immediate
+		imm := signExtend(instr>>20, 12)
+		return s.Register(rs1) + uint64(imm), size, true
+	case 0x23: // store: S-type immediate, split across the encoding
+		if funct3 > 3 {
+			return 0, 0, false // no byte/half/word/double-sized store uses funct3 4-6
+		}
+		imm := signExtend(((instr>>25)&0x7f)<<5|(instr>>7)&0x1f, 12)
+		return s.Register(rs1) + uint64(imm), size, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func signExtend(v uint64, bits int) int64 {
+	shift := 64 - bits
+	return int64(v<<shift) >> shift
+}
+
+// Step tracks call depth by decoding jal/jalr (call) and "jalr x0, 0(ra)"
+// (return) before running the instruction, so traces can report call-stack
+// depth alongside PC and symbol even though fast.VMState doesn't track it
+// itself.
+func (s *state) Step(proof bool) error {
+	instr := uint64(s.Instr())
+	switch instr & 0x7f {
+	case 0x6f: // jal
+		if rd := (instr >> 7) & 0x1f; rd == 1 {
+			s.callDepth++ // jal ra, ... links a return address; jal x0, ... (aka j) is a plain jump
+		}
+	case 0x67: // jalr
+		rd, rs1, imm := (instr>>7)&0x1f, (instr>>15)&0x1f, instr>>20
+		if rd == 0 && rs1 == 1 && imm == 0 && s.callDepth > 0 {
+			s.callDepth-- // jalr x0, 0(ra) == ret
+		} else if rd == 1 {
+			s.callDepth++
+		}
+	}
+
+	_, err := s.instState.Step(proof)
+	return err
+}
@@ -0,0 +1,114 @@
+// Package cannonbackend implements fpvmtest.VMBackend for the MIPS Cannon VM.
+// It is its own package (rather than living in fpvmtest directly) so that
+// consumers of the Asterisc backend don't have to pull in mipsevm and its
+// dependency tree.
+package cannonbackend
+
+import (
+	"debug/elf"
+	"io"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+
+	"github.com/anton-rs/kona/fpvm-tests/fpvmtest"
+)
+
+// Backend drives a fault-proof program through the MIPS Cannon VM.
+type Backend struct{}
+
+var _ fpvmtest.VMBackend = Backend{}
+
+func (Backend) Name() string { return "cannon" }
+
+func (Backend) LoadELF(programELF *elf.File, po fpvmtest.PreimageOracle, stdOut, stdErr io.Writer) (fpvmtest.VMState, fpvmtest.Symbols, error) {
+	state, err := mipsevm.LoadELF(programELF)
+	if err != nil {
+		return nil, nil, err
+	}
+	symbols, err := fpvmtest.NewSortedSymbols(programELF)
+	if err != nil {
+		return nil, nil, err
+	}
+	instState := mipsevm.NewInstrumentedState(state, po, stdOut, stdErr)
+	return &vmState{state: state, instState: instState}, symbols, nil
+}
+
+type vmState struct {
+	state     *mipsevm.State
+	instState *mipsevm.InstrumentedState
+	callDepth uint64
+}
+
+var (
+	_ fpvmtest.VMState    = (*vmState)(nil)
+	_ fpvmtest.TraceHooks = (*vmState)(nil)
+)
+
+func (s *vmState) PC() uint64            { return uint64(s.state.PC) }
+func (s *vmState) Register(i int) uint64 { return uint64(s.state.Registers[i]) }
+func (s *vmState) Instr() uint32         { return s.state.Memory.GetMemory(s.state.PC) }
+func (s *vmState) Exited() bool          { return s.state.Exited }
+func (s *vmState) ExitCode() uint8       { return s.state.ExitCode }
+func (s *vmState) CallDepth() uint64     { return s.callDepth }
+
+func (s *vmState) ReadMemoryRange(addr, size uint64) []byte {
+	r := s.state.Memory.ReadMemoryRange(uint32(addr), uint32(size))
+	dat, _ := io.ReadAll(r)
+	return dat
+}
+
+// Syscall reports the number in $v0 (register 2) when the current
+// instruction is a MIPS SYSCALL (opcode SPECIAL, funct 0x0c).
+func (s *vmState) Syscall() (num uint64, ok bool) {
+	instr := s.Instr()
+	if opcode, funct := instr>>26, instr&0x3f; opcode == 0 && funct == 0x0c {
+		return s.Register(2), true
+	}
+	return 0, false
+}
+
+// MemoryAccess reports the effective address and size of the load/store the
+// current MIPS instruction performs, if any: opcodes 0x20-0x26 are the
+// LB/LH/LWL/LW/LBU/LHU/LWR loads, 0x28-0x2e the SB/SH/SWL/SW/SWR stores.
+func (s *vmState) MemoryAccess() (addr, size uint64, ok bool) {
+	instr := s.Instr()
+	switch instr >> 26 {
+	case 0x20, 0x24: // LB, LBU
+		size = 1
+	case 0x21, 0x25: // LH, LHU
+		size = 2
+	case 0x22, 0x23, 0x26: // LWL, LW, LWR
+		size = 4
+	case 0x28: // SB
+		size = 1
+	case 0x29: // SH
+		size = 2
+	case 0x2a, 0x2b, 0x2e: // SWL, SW, SWR
+		size = 4
+	default:
+		return 0, 0, false
+	}
+
+	rs := int((instr >> 21) & 0x1f)
+	imm := int64(int16(instr & 0xffff))
+	return s.Register(rs) + uint64(imm), size, true
+}
+
+// Step tracks call depth by decoding jal/jalr (call) and "jr $ra" (return)
+// before running the instruction, so traces can report call-stack depth
+// alongside PC and symbol even though mipsevm doesn't track it itself.
+func (s *vmState) Step(proof bool) error {
+	instr := s.Instr()
+	opcode, funct, rs := instr>>26, instr&0x3f, (instr>>21)&0x1f
+	switch {
+	case opcode == 0x03: // jal
+		s.callDepth++
+	case opcode == 0 && funct == 0x09: // jalr
+		s.callDepth++
+	case opcode == 0 && funct == 0x08 && rs == 31 && s.callDepth > 0: // jr $ra
+		s.callDepth--
+	}
+
+	_, err := s.instState.Step(proof)
+	return err
+}
@@ -0,0 +1,187 @@
+// Package fpvmtest provides a VM-agnostic test harness for fault-proof
+// programs, so a single test body can be run against every supported FPVM
+// backend (Cannon, Asterisc, ...) instead of duplicating the step loop,
+// oracle plumbing and exit-code checks per VM.
+package fpvmtest
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+	"os"
+	"sort"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+// PreimageOracle is the subset of the on-chain preimage oracle interface the
+// shared harness needs from both mipsevm and asterisc.
+type PreimageOracle interface {
+	Hint(v []byte)
+	GetPreimage(k [32]byte) []byte
+}
+
+type testOracle struct {
+	hint        func(v []byte)
+	getPreimage func(k [32]byte) []byte
+}
+
+func (t *testOracle) Hint(v []byte) {
+	t.hint(v)
+}
+
+func (t *testOracle) GetPreimage(k [32]byte) []byte {
+	return t.getPreimage(k)
+}
+
+var _ PreimageOracle = (*testOracle)(nil)
+
+// NewOracle builds a PreimageOracle from a pair of hint/get-preimage
+// callbacks, for fault-proof programs whose preimage set doesn't match the
+// one RustTestOracle provides.
+func NewOracle(hint func(v []byte), getPreimage func(k [32]byte) []byte) PreimageOracle {
+	return &testOracle{hint: hint, getPreimage: getPreimage}
+}
+
+// Symbols abstracts the per-backend symbol table so trace hooks like the
+// runtime.throw watch in FullTest can be written once against either FPVM.
+type Symbols interface {
+	FindSymbol(pc uint64) elf.Symbol
+}
+
+// SortedSymbols implements Symbols by scanning an ELF binary's symbol table
+// directly, sorted by address. Backends that don't already expose their own
+// symbolizer (Cannon) can build one of these from the program ELF.
+type SortedSymbols []elf.Symbol
+
+// NewSortedSymbols reads and sorts programELF's symbol table by address.
+func NewSortedSymbols(programELF *elf.File) (SortedSymbols, error) {
+	syms, err := programELF.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(syms, func(i, j int) bool { return syms[i].Value < syms[j].Value })
+	return SortedSymbols(syms), nil
+}
+
+func (s SortedSymbols) FindSymbol(pc uint64) elf.Symbol {
+	i := sort.Search(len(s), func(i int) bool { return s[i].Value > pc }) - 1
+	if i < 0 || i >= len(s) {
+		return elf.Symbol{}
+	}
+	return s[i]
+}
+
+// VMState is the per-backend state a single FPVM step operates on. It is
+// intentionally narrow: just enough for FullTest to drive the step loop and
+// report failures the same way for every backend.
+type VMState interface {
+	PC() uint64
+	Register(i int) uint64
+	Instr() uint32
+	ReadMemoryRange(addr, size uint64) []byte
+	Exited() bool
+	ExitCode() uint8
+	Step(proof bool) error
+}
+
+// VMBackend converts a fault-proof ELF program into a VM-specific
+// instrumented state and exposes the handful of operations FullTest needs to
+// drive it, so the same test body runs unmodified against every backend.
+type VMBackend interface {
+	// Name identifies the backend in test failure messages, e.g. "cannon".
+	Name() string
+	// LoadELF parses programELF into this backend's VM state, wired up to
+	// stdOut/stdErr and the given preimage oracle.
+	LoadELF(programELF *elf.File, po PreimageOracle, stdOut, stdErr io.Writer) (VMState, Symbols, error)
+}
+
+// FullTest runs the fault-proof program at programPath through backend to
+// completion, asserting that it exits cleanly, and returns its captured
+// stdout/stderr. It is shared by every FPVM backend so a new fault-proof
+// program only needs a single table-driven entry to be exercised everywhere.
+func FullTest(t *testing.T, backend VMBackend, programPath string, po PreimageOracle) (stdOut, stdErr bytes.Buffer) {
+	programELF, err := elf.Open(programPath)
+	require.NoError(t, err, "open ELF file")
+	defer programELF.Close()
+
+	var tracer *ExecutionTracer
+	if *traceFile != "" {
+		tracer, err = NewExecutionTracer(*traceFile)
+		require.NoError(t, err, "open -trace file")
+		defer func() {
+			if *traceProfileFile != "" {
+				f, err := os.Create(*traceProfileFile)
+				require.NoError(t, err, "open -traceprofile file")
+				require.NoError(t, tracer.WriteProfile(f), "write pprof profile")
+				require.NoError(t, f.Close())
+			}
+			require.NoError(t, tracer.Close(), "close -trace file")
+		}()
+	}
+
+	var stepIdx uint64
+	if tracer != nil {
+		po = tracingOracle{inner: po, tracer: tracer, step: &stepIdx}
+	}
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	state, symbols, err := backend.LoadELF(programELF, po, io.MultiWriter(os.Stdout, &stdOutBuf), io.MultiWriter(os.Stderr, &stdErrBuf))
+	require.NoError(t, err, "must load test suite ELF binary")
+	hooks, _ := state.(TraceHooks)
+
+	var lastSym elf.Symbol
+	for i := uint64(0); i < 2_000_000; i++ {
+		stepIdx = i
+		sym := symbols.FindSymbol(state.PC())
+
+		if sym.Name != lastSym.Name {
+			t.Logf("i: %4d  pc: 0x%x  instr: %08x  symbol name: %s size: %d", i, state.PC(), state.Instr(), sym.Name, sym.Size)
+		}
+		lastSym = sym
+
+		if tracer != nil {
+			st := StepTrace{Step: i, PC: state.PC(), Symbol: sym.Name}
+			if hooks != nil {
+				st.Depth = hooks.CallDepth()
+				st.SyscallNum, st.HasSyscall = hooks.Syscall()
+				st.MemAddr, st.MemSize, st.HasMemAccess = hooks.MemoryAccess()
+			}
+			require.NoError(t, tracer.RecordStep(st), "write execution trace")
+		}
+
+		if sym.Name == "runtime.throw" {
+			// Registers 10 and 11 are the RISC-V a0/a1 argument registers
+			// (Asterisc). On Cannon they are MIPS $t2/$t3, which do not hold
+			// throw's message, so the logged bytes are not its argument there.
+			throwArg := state.Register(10)
+			throwArgLen := state.Register(11)
+			if throwArgLen > 1000 {
+				throwArgLen = 1000
+			}
+			dat := state.ReadMemoryRange(throwArg, throwArgLen)
+			if utf8.Valid(dat) {
+				t.Logf("THROW! %q", string(dat))
+			} else {
+				t.Logf("THROW! %016x: %x", throwArg, dat)
+			}
+			break
+		}
+
+		err := state.Step(false)
+		require.NoError(t, err, "%s VM must run step", backend.Name())
+
+		if state.Exited() {
+			break
+		}
+	}
+
+	require.True(t, state.Exited(), "ran out of steps")
+	if code := state.ExitCode(); code != 0 {
+		t.Fatalf("%s: failed with exit code %d", backend.Name(), code)
+	}
+
+	return stdOutBuf, stdErrBuf
+}
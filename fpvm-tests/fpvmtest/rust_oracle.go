@@ -0,0 +1,54 @@
+package fpvmtest
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+)
+
+// RustTestOracle builds the PreimageOracle shared by the simple-revm and
+// minimal fault-proof programs across every supported FPVM backend.
+func RustTestOracle(t *testing.T) PreimageOracle {
+	images := make(map[[32]byte][]byte)
+	sha2Preimages := make(map[[32]byte][]byte)
+
+	input := []byte("facade facade facade")
+	shaHash := sha256.Sum256(input)
+	images[preimage.LocalIndexKey(1).PreimageKey()] = shaHash[:]
+	sha2Preimages[shaHash] = input
+
+	// CALLDATASIZE
+	// PUSH0
+	// PUSH0
+	// CALLDATACOPY
+	// CALLDATASIZE
+	// PUSH0
+	// RETURN
+	images[preimage.LocalIndexKey(2).PreimageKey()] = common.Hex2Bytes("365f5f37365ff3")
+
+	return NewOracle(
+		func(v []byte) {
+			hintStr := string(v)
+			hintParts := strings.Split(hintStr, " ")
+
+			switch hintParts[0] {
+			case "sha2-preimage":
+				hash := common.HexToHash(hintParts[1])
+				images[preimage.LocalIndexKey(0).PreimageKey()] = sha2Preimages[hash]
+			default:
+				t.Fatalf("unknown hint: %s", hintStr)
+			}
+		},
+		func(k [32]byte) []byte {
+			p, ok := images[k]
+			if !ok {
+				t.Fatalf("missing pre-image %s", k)
+			}
+			return p
+		},
+	)
+}
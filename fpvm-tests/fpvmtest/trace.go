@@ -0,0 +1,381 @@
+package fpvmtest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/pprof/profile"
+)
+
+var (
+	traceFile        = flag.String("trace", "", "write a structured execution trace of the FullTest run to this file")
+	traceProfileFile = flag.String("traceprofile", "", "write a pprof-compatible CPU profile keyed by symbol to this file (only written when -trace is also set)")
+)
+
+// Trace record kinds. Each record starts with one of these as its first
+// byte, followed by a step index and kind-specific fields.
+const (
+	recordKindStep         = 0
+	recordKindPreimageHint = 1
+	recordKindPreimageGet  = 2
+)
+
+// traceMagic/traceVersion identify the file format decoders key off of.
+const (
+	traceMagic   = "KFPT"
+	traceVersion = 2
+)
+
+// TraceHooks is an optional VMState extension exposing the per-step call
+// depth, syscall number and memory region touched that a backend can derive
+// by decoding its own instruction encoding (jal/jalr/ret, load/store on
+// RISC-V; jal/jalr/jr $ra, load/store on MIPS). Backends that don't
+// implement it are traced with depth 0, no syscalls and no memory accesses.
+type TraceHooks interface {
+	// CallDepth returns the call-stack depth after the most recently
+	// executed step.
+	CallDepth() uint64
+	// Syscall returns the syscall number the step about to run will invoke,
+	// if any.
+	Syscall() (num uint64, ok bool)
+	// MemoryAccess returns the address and size in bytes of the memory
+	// region the step about to run will load from or store to, if any.
+	MemoryAccess() (addr, size uint64, ok bool)
+}
+
+// StepTrace is the per-step data ExecutionTracer.RecordStep writes.
+type StepTrace struct {
+	Step   uint64
+	PC     uint64
+	Symbol string
+
+	Depth uint64
+
+	SyscallNum uint64
+	HasSyscall bool
+
+	MemAddr      uint64
+	MemSize      uint64
+	HasMemAccess bool
+}
+
+// ExecutionTracer records a FullTest run as a compact binary trace (PC,
+// symbol, call depth, syscalls and preimage-oracle interactions per step),
+// and can additionally summarize it as a pprof-compatible CPU profile keyed
+// by symbol name, so `go tool pprof` can show which Rust functions dominate
+// step count inside a fault-proof program.
+type ExecutionTracer struct {
+	w       *bufio.Writer
+	f       *os.File
+	samples map[string]int64
+}
+
+// NewExecutionTracer creates path and writes the trace header to it.
+func NewExecutionTracer(path string) (*ExecutionTracer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := w.WriteString(traceMagic); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := w.WriteByte(traceVersion); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ExecutionTracer{w: w, f: f, samples: make(map[string]int64)}, nil
+}
+
+// RecordStep appends a step record and tallies the symbol for WriteProfile.
+func (tr *ExecutionTracer) RecordStep(s StepTrace) error {
+	tr.samples[s.Symbol]++
+
+	if err := tr.w.WriteByte(recordKindStep); err != nil {
+		return err
+	}
+	for _, v := range [...]uint64{s.Step, s.PC, s.Depth, s.SyscallNum, s.MemAddr, s.MemSize} {
+		if err := writeUint64(tr.w, v); err != nil {
+			return err
+		}
+	}
+	flags := byte(0)
+	if s.HasSyscall {
+		flags |= 1
+	}
+	if s.HasMemAccess {
+		flags |= 2
+	}
+	if err := tr.w.WriteByte(flags); err != nil {
+		return err
+	}
+	return writeString(tr.w, s.Symbol)
+}
+
+// RecordHint appends a preimage-oracle hint record.
+func (tr *ExecutionTracer) RecordHint(step uint64, hint []byte) error {
+	if err := tr.w.WriteByte(recordKindPreimageHint); err != nil {
+		return err
+	}
+	if err := writeUint64(tr.w, step); err != nil {
+		return err
+	}
+	return writeBytes(tr.w, hint)
+}
+
+// RecordPreimage appends a preimage-oracle get-preimage record.
+func (tr *ExecutionTracer) RecordPreimage(step uint64, key [32]byte, value []byte) error {
+	if err := tr.w.WriteByte(recordKindPreimageGet); err != nil {
+		return err
+	}
+	if err := writeUint64(tr.w, step); err != nil {
+		return err
+	}
+	if _, err := tr.w.Write(key[:]); err != nil {
+		return err
+	}
+	return writeUint32(tr.w, uint32(len(value)))
+}
+
+// WriteProfile writes a pprof-compatible CPU profile, with one sample per
+// symbol weighted by the number of steps spent in it, to w.
+func (tr *ExecutionTracer) WriteProfile(w io.Writer) error {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "steps", Unit: "count"}},
+		PeriodType: &profile.ValueType{Type: "step", Unit: "count"},
+		Period:     1,
+	}
+	id := uint64(1)
+	for symbol, count := range tr.samples {
+		name := symbol
+		if name == "" {
+			name = "?"
+		}
+		fn := &profile.Function{ID: id, Name: name}
+		loc := &profile.Location{ID: id, Line: []profile.Line{{Function: fn}}}
+		p.Function = append(p.Function, fn)
+		p.Location = append(p.Location, loc)
+		p.Sample = append(p.Sample, &profile.Sample{Location: []*profile.Location{loc}, Value: []int64{count}})
+		id++
+	}
+	return p.Write(w)
+}
+
+// Close flushes the trace to disk.
+func (tr *ExecutionTracer) Close() error {
+	if err := tr.w.Flush(); err != nil {
+		tr.f.Close()
+		return err
+	}
+	return tr.f.Close()
+}
+
+func writeUint64(w *bufio.Writer, v uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func writeUint32(w *bufio.Writer, v uint32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func writeString(w *bufio.Writer, s string) error {
+	return writeBytes(w, []byte(s))
+}
+
+func writeBytes(w *bufio.Writer, b []byte) error {
+	if err := writeUint32(w, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// tracingOracle wraps a PreimageOracle to record every hint/get-preimage
+// interaction against the step in progress when FullTest calls it.
+type tracingOracle struct {
+	inner  PreimageOracle
+	tracer *ExecutionTracer
+	step   *uint64
+}
+
+func (o tracingOracle) Hint(v []byte) {
+	if err := o.tracer.RecordHint(*o.step, v); err != nil {
+		panic(err) // the trace file is a developer diagnostic, not test output we can degrade gracefully
+	}
+	o.inner.Hint(v)
+}
+
+func (o tracingOracle) GetPreimage(k [32]byte) []byte {
+	v := o.inner.GetPreimage(k)
+	if err := o.tracer.RecordPreimage(*o.step, k, v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+var _ PreimageOracle = tracingOracle{}
+
+// TraceEventKind identifies which fields of a TraceEvent are populated.
+type TraceEventKind int
+
+const (
+	EventStep TraceEventKind = iota
+	EventPreimageHint
+	EventPreimageGet
+)
+
+// TraceEvent is a single decoded record from a trace written by
+// ExecutionTracer. Only the fields relevant to Kind are populated.
+type TraceEvent struct {
+	Kind TraceEventKind
+	Step uint64
+
+	// EventStep
+	PC           uint64
+	Depth        uint64
+	SyscallNum   uint64
+	HasSyscall   bool
+	MemAddr      uint64
+	MemSize      uint64
+	HasMemAccess bool
+	Symbol       string
+
+	// EventPreimageHint
+	Hint []byte
+
+	// EventPreimageGet
+	PreimageKey [32]byte
+	PreimageLen uint32
+}
+
+// ReadTrace decodes every event written by an ExecutionTracer, for tooling
+// like kona-fpvm-trace that inspects or diffs trace files.
+func ReadTrace(r io.Reader) ([]TraceEvent, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(traceMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("read trace magic: %w", err)
+	}
+	if string(magic) != traceMagic {
+		return nil, fmt.Errorf("not a fpvmtest trace file (magic %q)", magic)
+	}
+	version, err := br.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("read trace version: %w", err)
+	}
+	if version != traceVersion {
+		return nil, fmt.Errorf("unsupported trace version %d", version)
+	}
+
+	var events []TraceEvent
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case recordKindStep:
+			ev := TraceEvent{Kind: EventStep}
+			if ev.Step, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.PC, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.Depth, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.SyscallNum, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.MemAddr, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.MemSize, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			flags, err := br.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			ev.HasSyscall = flags&1 != 0
+			ev.HasMemAccess = flags&2 != 0
+			if ev.Symbol, err = readString(br); err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		case recordKindPreimageHint:
+			ev := TraceEvent{Kind: EventPreimageHint}
+			if ev.Step, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if ev.Hint, err = readBytesRecord(br); err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		case recordKindPreimageGet:
+			ev := TraceEvent{Kind: EventPreimageGet}
+			if ev.Step, err = readUint64(br); err != nil {
+				return nil, err
+			}
+			if _, err := io.ReadFull(br, ev.PreimageKey[:]); err != nil {
+				return nil, err
+			}
+			if ev.PreimageLen, err = readUint32(br); err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		default:
+			return nil, fmt.Errorf("unknown trace record kind %d", kind)
+		}
+	}
+}
+
+func readUint64(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func readString(r io.Reader) (string, error) {
+	b, err := readBytesRecord(r)
+	return string(b), err
+}
+
+func readBytesRecord(r io.Reader) ([]byte, error) {
+	n, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,130 @@
+// Command kona-fpvm-trace decodes the binary trace files ExecutionTracer
+// writes (see fpvm-tests/fpvmtest/trace.go) for inspection, and diffs two of
+// them step-by-step to find the first point a fault-proof program ran
+// differently between VM backends.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/anton-rs/kona/fpvm-tests/fpvmtest"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "decode":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		err = decode(os.Args[2])
+	case "diff":
+		if len(os.Args) != 4 {
+			usage()
+		}
+		err = diff(os.Args[2], os.Args[3])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "kona-fpvm-trace:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kona-fpvm-trace decode <trace.bin>")
+	fmt.Fprintln(os.Stderr, "       kona-fpvm-trace diff <a.bin> <b.bin>")
+	os.Exit(2)
+}
+
+func readTrace(path string) ([]fpvmtest.TraceEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return fpvmtest.ReadTrace(f)
+}
+
+func decode(path string) error {
+	events, err := readTrace(path)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		fmt.Println(formatEvent(ev))
+	}
+	return nil
+}
+
+func diff(pathA, pathB string) error {
+	a, err := readTrace(pathA)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", pathA, err)
+	}
+	b, err := readTrace(pathB)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", pathB, err)
+	}
+
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if !eventsEqual(a[i], b[i]) {
+			fmt.Printf("first divergence at record %d:\n  %s: %s\n  %s: %s\n", i, pathA, formatEvent(a[i]), pathB, formatEvent(b[i]))
+			return nil
+		}
+	}
+	if len(a) != len(b) {
+		fmt.Printf("traces agree for the first %d records, then %s has %d and %s has %d\n", n, pathA, len(a), pathB, len(b))
+		return nil
+	}
+	fmt.Println("traces are identical")
+	return nil
+}
+
+func eventsEqual(a, b fpvmtest.TraceEvent) bool {
+	return a.Kind == b.Kind &&
+		a.Step == b.Step &&
+		a.PC == b.PC &&
+		a.Depth == b.Depth &&
+		a.SyscallNum == b.SyscallNum &&
+		a.HasSyscall == b.HasSyscall &&
+		a.MemAddr == b.MemAddr &&
+		a.MemSize == b.MemSize &&
+		a.HasMemAccess == b.HasMemAccess &&
+		a.Symbol == b.Symbol &&
+		bytes.Equal(a.Hint, b.Hint) &&
+		a.PreimageKey == b.PreimageKey &&
+		a.PreimageLen == b.PreimageLen
+}
+
+func formatEvent(ev fpvmtest.TraceEvent) string {
+	switch ev.Kind {
+	case fpvmtest.EventStep:
+		sc := "-"
+		if ev.HasSyscall {
+			sc = fmt.Sprintf("%d", ev.SyscallNum)
+		}
+		mem := "-"
+		if ev.HasMemAccess {
+			mem = fmt.Sprintf("0x%x+%d", ev.MemAddr, ev.MemSize)
+		}
+		return fmt.Sprintf("step=%d pc=0x%x depth=%d syscall=%s mem=%s symbol=%s", ev.Step, ev.PC, ev.Depth, sc, mem, ev.Symbol)
+	case fpvmtest.EventPreimageHint:
+		return fmt.Sprintf("step=%d hint=%q", ev.Step, ev.Hint)
+	case fpvmtest.EventPreimageGet:
+		return fmt.Sprintf("step=%d preimage key=%x len=%d", ev.Step, ev.PreimageKey, ev.PreimageLen)
+	default:
+		return fmt.Sprintf("step=%d unknown event kind %d", ev.Step, ev.Kind)
+	}
+}